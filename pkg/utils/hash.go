@@ -4,11 +4,21 @@ import (
 	"crypto/md5"
 	"crypto/sha1"
 	"crypto/sha256"
+	"crypto/sha512"
 	"encoding/hex"
 	"errors"
+	"fmt"
 	"github.com/alist-org/alist/v3/internal/errs"
+	"github.com/alist-org/alist/v3/pkg/utils/hashes/dropbox"
+	"github.com/alist-org/alist/v3/pkg/utils/hashes/gcid"
+	"github.com/alist-org/alist/v3/pkg/utils/hashes/mailru"
+	"github.com/alist-org/alist/v3/pkg/utils/hashes/quickxor"
+	"github.com/zeebo/blake3"
 	"hash"
+	"hash/crc32"
+	"hash/crc64"
 	"io"
+	"sort"
 	"strings"
 )
 
@@ -24,10 +34,12 @@ var ErrUnsupported = errors.New("hash type not supported")
 
 // HashType indicates a standard hashing algorithm
 type HashType struct {
-	Width   int
-	Name    string
-	Alias   string
-	NewFunc func() hash.Hash
+	Width    int
+	Name     string
+	Alias    string
+	NewFunc  func() hash.Hash
+	id       uint // bit position of this type within a HashSet
+	strength int  // used to rank hash types from weakest to strongest; higher wins
 }
 
 var (
@@ -36,14 +48,18 @@ var (
 	Supported  []*HashType
 )
 
-// RegisterHash adds a new Hash to the list and returns its Type
+// RegisterHash adds a new Hash to the list and returns its Type. Its
+// strength for HashSet.Overlap/HashInfo.ToCID purposes is its
+// registration order: later-registered algorithms are assumed stronger.
 func RegisterHash(name, alias string, width int, newFunc func() hash.Hash) *HashType {
 
 	newType := &HashType{
-		Name:    name,
-		Alias:   alias,
-		Width:   width,
-		NewFunc: newFunc,
+		Name:     name,
+		Alias:    alias,
+		Width:    width,
+		NewFunc:  newFunc,
+		id:       uint(len(Supported)),
+		strength: len(Supported),
 	}
 
 	name2hash[name] = newType
@@ -52,6 +68,108 @@ func RegisterHash(name, alias string, width int, newFunc func() hash.Hash) *Hash
 	return newType
 }
 
+// checksumStrength is the strength band for non-cryptographic checksums
+// (CRC and friends): always weaker than every cryptographic hash, no
+// matter when it was registered relative to them.
+const checksumStrength = -1 << 30
+
+// RegisterChecksumHash is like RegisterHash, but for non-cryptographic
+// checksums such as CRC32/CRC64. These are ranked below every
+// cryptographic hash regardless of registration order, so HashSet.Overlap
+// and HashInfo.ToCID never prefer a checksum over a real hash just
+// because it happened to be registered later.
+func RegisterChecksumHash(name, alias string, width int, newFunc func() hash.Hash) *HashType {
+	newType := RegisterHash(name, alias, width, newFunc)
+	newType.strength = checksumStrength + int(newType.id)
+	return newType
+}
+
+// ByName finds a hash by its name, returning an error listing every
+// supported name if it can't be found (mirroring rclone's behavior of
+// printing the supported hash list on a misspelled name).
+func ByName(name string) (*HashType, error) {
+	if ht, ok := name2hash[name]; ok {
+		return ht, nil
+	}
+	return nil, fmt.Errorf("hash type %q not found, supported: %s", name, supportedNames())
+}
+
+// ByAlias finds a hash by its alias, returning an error listing every
+// supported name if it can't be found.
+func ByAlias(alias string) (*HashType, error) {
+	if ht, ok := alias2hash[alias]; ok {
+		return ht, nil
+	}
+	return nil, fmt.Errorf("hash type alias %q not found, supported: %s", alias, supportedNames())
+}
+
+func supportedNames() string {
+	names := make([]string, 0, len(Supported))
+	for _, ht := range Supported {
+		names = append(names, ht.Name)
+	}
+	return strings.Join(names, ", ")
+}
+
+// HashSet is a set of HashTypes, stored as a bitmask so it can be passed
+// and compared cheaply. Drivers advertise the hashes they can compute via
+// Hashes() HashSet, and the copy/verify engine negotiates a common one
+// with Overlap.
+type HashSet uint64
+
+// NewHashSet returns a HashSet containing the given hash types.
+func NewHashSet(types ...*HashType) HashSet {
+	var s HashSet
+	for _, ht := range types {
+		s = s.Add(ht)
+	}
+	return s
+}
+
+// Contains reports whether ht is in the set.
+func (s HashSet) Contains(ht *HashType) bool {
+	return ht != nil && s&(1<<ht.id) != 0
+}
+
+// Add returns a new HashSet with ht added.
+func (s HashSet) Add(ht *HashType) HashSet {
+	return s | 1<<ht.id
+}
+
+// strongestFirst returns every registered hash type ordered from
+// strongest to weakest, per HashType.strength. It is the single source
+// of truth for "strongest hash" used by both Overlap and HashInfo.ToCID.
+func strongestFirst() []*HashType {
+	out := append([]*HashType(nil), Supported...)
+	sort.SliceStable(out, func(i, j int) bool {
+		return out[i].strength > out[j].strength
+	})
+	return out
+}
+
+// Overlap returns the strongest HashType present in both s and other, or
+// nil if they share none.
+func (s HashSet) Overlap(other HashSet) *HashType {
+	common := s & other
+	for _, ht := range strongestFirst() {
+		if common&(1<<ht.id) != 0 {
+			return ht
+		}
+	}
+	return nil
+}
+
+// Array returns the HashTypes contained in s, in registration order.
+func (s HashSet) Array() []*HashType {
+	var out []*HashType
+	for _, ht := range Supported {
+		if s.Contains(ht) {
+			out = append(out, ht)
+		}
+	}
+	return out
+}
+
 var (
 	// MD5 indicates MD5 support
 	MD5 = RegisterHash("md5", "MD5", 32, md5.New)
@@ -61,8 +179,71 @@ var (
 
 	// SHA256 indicates SHA-256 support
 	SHA256 = RegisterHash("sha256", "SHA-256", 64, sha256.New)
+
+	// DropboxHash indicates Dropbox's content hash support
+	DropboxHash = RegisterHash("dropbox", "Dropbox-Content-Hash", 64, dropbox.New)
+
+	// QuickXorHash indicates OneDrive's QuickXorHash support. It's a
+	// 160-bit rolling XOR, not a cryptographic hash, so like the CRCs
+	// below it's always ranked below MD5 and friends by HashSet.Overlap
+	// and HashInfo.ToCID.
+	QuickXorHash = RegisterChecksumHash("quickxor", "QuickXorHash", 40, quickxor.New)
+
+	// MailruHash indicates Mail.ru's content hash support. It's a tree of
+	// SHA-1 blocks combined with plain concatenation rather than a
+	// single SHA-1/SHA-256 run, so it's ranked below the stdlib crypto
+	// hashes too.
+	MailruHash = RegisterChecksumHash("mailru", "Mailru-Hash", 40, mailru.New)
+
+	// GCID indicates 115's GCID support. Same reasoning as MailruHash:
+	// it's a composite of block SHA-1 digests, weaker than a straight
+	// SHA-1/SHA-256 run.
+	GCID = RegisterChecksumHash("gcid", "GCID", 40, gcid.New)
+
+	// SHA512 indicates SHA-512 support
+	SHA512 = RegisterHash("sha512", "SHA-512", 128, sha512.New)
+
+	// CRC32 indicates CRC-32 (IEEE polynomial) support. It's a checksum,
+	// not a cryptographic hash, so it's always ranked below MD5 and
+	// friends by HashSet.Overlap and HashInfo.ToCID.
+	CRC32 = RegisterChecksumHash("crc32", "CRC-32", 8, func() hash.Hash { return crc32.NewIEEE() })
+
+	// CRC32C indicates CRC-32 (Castagnoli polynomial) support, matching
+	// the chunk checksums returned by S3 and GCS
+	CRC32C = RegisterChecksumHash("crc32c", "CRC-32C", 8, func() hash.Hash { return crc32.New(crc32.MakeTable(crc32.Castagnoli)) })
+
+	// CRC64 indicates CRC-64 (ISO polynomial) support
+	CRC64 = RegisterChecksumHash("crc64", "CRC-64", 16, func() hash.Hash { return crc64.New(crc64.MakeTable(crc64.ISO)) })
+
+	// CRC64ECMA indicates CRC-64 (ECMA polynomial) support
+	CRC64ECMA = RegisterChecksumHash("crc64ecma", "CRC-64/ECMA", 16, func() hash.Hash { return crc64.New(crc64.MakeTable(crc64.ECMA)) })
+
+	// BLAKE3 indicates BLAKE3 support. It is dramatically faster than
+	// SHA-256 on the multi-GB uploads alist routinely handles, so it's
+	// the preferred choice whenever both sides of a transfer support it
+	// and checkpointing mid-upload isn't required: unlike the stdlib
+	// hashes and the proprietary ones above, the zeebo/blake3 digest
+	// doesn't implement encoding.BinaryMarshaler, so MultiHasher can't
+	// checkpoint a BLAKE3 run for a chunked/resumable upload yet.
+	BLAKE3 = RegisterHash("blake3", "BLAKE3", 64, func() hash.Hash { return blake3.New() })
 )
 
+// RegisterHashFromName resolves a canonical algorithm name to its
+// registered HashType, trying it as a name and then as an alias (the
+// same name2hash/alias2hash tables ByName/ByAlias use), so config and
+// driver code can request a hash symbolically without importing every
+// crypto package directly, and without a second hand-maintained list of
+// spellings to keep in sync as new hashes are registered.
+func RegisterHashFromName(name string) (*HashType, error) {
+	if ht, err := ByName(name); err == nil {
+		return ht, nil
+	}
+	if ht, err := ByAlias(name); err == nil {
+		return ht, nil
+	}
+	return nil, fmt.Errorf("hash type %q not found, supported: %s", name, supportedNames())
+}
+
 // HashData get hash of one hashType
 func HashData(hashType *HashType, data []byte) string {
 	h := hashType.NewFunc()
@@ -0,0 +1,131 @@
+package utils
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestByName(t *testing.T) {
+	ht, err := ByName("sha256")
+	if err != nil || ht != SHA256 {
+		t.Errorf("ByName(sha256) = %v, %v, want SHA256, nil", ht, err)
+	}
+	if _, err := ByName("not-a-hash"); err == nil {
+		t.Error("ByName(not-a-hash) should have failed")
+	}
+}
+
+func TestByAlias(t *testing.T) {
+	ht, err := ByAlias("SHA-256")
+	if err != nil || ht != SHA256 {
+		t.Errorf("ByAlias(SHA-256) = %v, %v, want SHA256, nil", ht, err)
+	}
+	if _, err := ByAlias("not-a-hash"); err == nil {
+		t.Error("ByAlias(not-a-hash) should have failed")
+	}
+}
+
+func TestRegisterHashFromName(t *testing.T) {
+	// Resolves by canonical name.
+	if ht, err := RegisterHashFromName("md5"); err != nil || ht != MD5 {
+		t.Errorf("RegisterHashFromName(md5) = %v, %v, want MD5, nil", ht, err)
+	}
+	// Resolves by alias too.
+	if ht, err := RegisterHashFromName("SHA-256"); err != nil || ht != SHA256 {
+		t.Errorf("RegisterHashFromName(SHA-256) = %v, %v, want SHA256, nil", ht, err)
+	}
+	if _, err := RegisterHashFromName("not-a-hash"); err == nil {
+		t.Error("RegisterHashFromName(not-a-hash) should have failed")
+	}
+}
+
+func TestHashSetContainsAndAdd(t *testing.T) {
+	s := NewHashSet(MD5, SHA256)
+	if !s.Contains(MD5) || !s.Contains(SHA256) {
+		t.Error("set should contain MD5 and SHA256")
+	}
+	if s.Contains(SHA1) {
+		t.Error("set should not contain SHA1")
+	}
+	s = s.Add(SHA1)
+	if !s.Contains(SHA1) {
+		t.Error("set should contain SHA1 after Add")
+	}
+}
+
+func TestHashSetOverlapPrefersStrongerHash(t *testing.T) {
+	s := NewHashSet(SHA512, CRC32C)
+	if got := s.Overlap(s); got != SHA512 {
+		t.Errorf("Overlap = %v, want SHA512 (checksums must never outrank cryptographic hashes)", got)
+	}
+}
+
+func TestHashSetOverlapPrefersCryptoHashOverQuickXor(t *testing.T) {
+	s := NewHashSet(SHA256, QuickXorHash)
+	if got := s.Overlap(s); got != SHA256 {
+		t.Errorf("Overlap = %v, want SHA256 (QuickXorHash must never outrank a stdlib crypto hash)", got)
+	}
+}
+
+func TestHashSetOverlapNone(t *testing.T) {
+	a := NewHashSet(MD5)
+	b := NewHashSet(SHA256)
+	if got := a.Overlap(b); got != nil {
+		t.Errorf("Overlap = %v, want nil", got)
+	}
+}
+
+func TestHashSetArray(t *testing.T) {
+	s := NewHashSet(SHA256, MD5)
+	arr := s.Array()
+	if len(arr) != 2 {
+		t.Fatalf("Array() len = %d, want 2", len(arr))
+	}
+	// Array is in registration order, not insertion order.
+	if arr[0] != MD5 || arr[1] != SHA256 {
+		t.Errorf("Array() = %v, want [MD5 SHA256]", arr)
+	}
+}
+
+func TestMultiHasherResume(t *testing.T) {
+	data := []byte("the quick brown fox jumps over the lazy dog")
+	split := len(data) / 2
+
+	m1 := NewMultiHasher([]*HashType{MD5, SHA256})
+	if _, err := m1.Write(data[:split]); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	state, err := m1.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary: %v", err)
+	}
+
+	m2 := &MultiHasher{}
+	if err := m2.UnmarshalBinary(state); err != nil {
+		t.Fatalf("UnmarshalBinary: %v", err)
+	}
+	if _, err := m2.Write(data[split:]); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if _, err := m1.Write(data[split:]); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	if m2.Size() != m1.Size() {
+		t.Errorf("resumed size = %d, want %d", m2.Size(), m1.Size())
+	}
+	for _, ht := range []*HashType{MD5, SHA256} {
+		want, err := m1.Sum(ht)
+		if err != nil {
+			t.Fatalf("Sum(%s): %v", ht.Name, err)
+		}
+		got, err := m2.Sum(ht)
+		if err != nil {
+			t.Fatalf("resumed Sum(%s): %v", ht.Name, err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("resumed Sum(%s) = %x, want %x", ht.Name, got, want)
+		}
+	}
+}
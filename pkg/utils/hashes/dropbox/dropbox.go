@@ -0,0 +1,127 @@
+// Package dropbox implements Dropbox's content hash algorithm.
+//
+// The input is split into 4 MiB blocks, each block is hashed with SHA-256,
+// and the concatenation of those block digests is hashed again with
+// SHA-256 to produce the final content hash.
+// See https://www.dropbox.com/developers/reference/content-hash
+package dropbox
+
+import (
+	"crypto/sha256"
+	"encoding"
+	"hash"
+
+	"github.com/alist-org/alist/v3/pkg/utils/hashes/internal/checkpoint"
+)
+
+const BlockSize = 4 * 1024 * 1024
+
+type digest struct {
+	block    hash.Hash
+	overall  hash.Hash
+	buffered int
+}
+
+// New returns a new hash.Hash computing the Dropbox content hash.
+func New() hash.Hash {
+	d := &digest{}
+	d.Reset()
+	return d
+}
+
+func (d *digest) Reset() {
+	d.block = sha256.New()
+	d.overall = sha256.New()
+	d.buffered = 0
+}
+
+func (d *digest) Write(p []byte) (n int, err error) {
+	n = len(p)
+	for len(p) > 0 {
+		free := BlockSize - d.buffered
+		if free > len(p) {
+			free = len(p)
+		}
+		d.block.Write(p[:free])
+		d.buffered += free
+		p = p[free:]
+		if d.buffered == BlockSize {
+			d.overall.Write(d.block.Sum(nil))
+			d.block.Reset()
+			d.buffered = 0
+		}
+	}
+	return n, nil
+}
+
+// Sum does not mutate the running state, so hashing can continue
+// after an intermediate Sum call.
+func (d *digest) Sum(b []byte) []byte {
+	overall := clone(d.overall)
+	if d.buffered > 0 {
+		overall.Write(d.block.Sum(nil))
+	}
+	return overall.Sum(b)
+}
+
+func (d *digest) Size() int { return sha256.Size }
+
+func (d *digest) BlockSize() int { return sha256.BlockSize }
+
+// clone duplicates a sha256 hash.Hash via its BinaryMarshaler state,
+// leaving the original untouched.
+func clone(h hash.Hash) hash.Hash {
+	state, err := h.(encoding.BinaryMarshaler).MarshalBinary()
+	if err != nil {
+		panic("dropbox: sha256 state is always marshalable: " + err.Error())
+	}
+	c := sha256.New()
+	if err = c.(encoding.BinaryUnmarshaler).UnmarshalBinary(state); err != nil {
+		panic("dropbox: sha256 state is always unmarshalable: " + err.Error())
+	}
+	return c
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler so a digest can be
+// checkpointed mid-upload and resumed later, e.g. by MultiHasher.
+func (d *digest) MarshalBinary() ([]byte, error) {
+	overallState, err := d.overall.(encoding.BinaryMarshaler).MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	blockState, err := d.block.(encoding.BinaryMarshaler).MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	var buf []byte
+	buf = checkpoint.AppendLenPrefixed(buf, overallState)
+	buf = checkpoint.AppendLenPrefixed(buf, blockState)
+	buf = checkpoint.AppendUvarint(buf, uint64(d.buffered))
+	return buf, nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (d *digest) UnmarshalBinary(data []byte) error {
+	overallState, data, err := checkpoint.ReadLenPrefixed(data)
+	if err != nil {
+		return err
+	}
+	blockState, data, err := checkpoint.ReadLenPrefixed(data)
+	if err != nil {
+		return err
+	}
+	buffered, _, err := checkpoint.ReadUvarint(data)
+	if err != nil {
+		return err
+	}
+	d.overall = sha256.New()
+	if err = d.overall.(encoding.BinaryUnmarshaler).UnmarshalBinary(overallState); err != nil {
+		return err
+	}
+	d.block = sha256.New()
+	if err = d.block.(encoding.BinaryUnmarshaler).UnmarshalBinary(blockState); err != nil {
+		return err
+	}
+	d.buffered = int(buffered)
+	return nil
+}
@@ -0,0 +1,51 @@
+package dropbox
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+)
+
+func TestDropboxEmpty(t *testing.T) {
+	want := hex.EncodeToString(sha256.New().Sum(nil))
+	h := New()
+	if got := hex.EncodeToString(h.Sum(nil)); got != want {
+		t.Errorf("sum(nil) = %s, want %s", got, want)
+	}
+}
+
+func TestDropboxSingleFullBlock(t *testing.T) {
+	block := make([]byte, BlockSize)
+	blockDigest := sha256.Sum256(block)
+	outerDigest := sha256.Sum256(blockDigest[:])
+	want := hex.EncodeToString(outerDigest[:])
+
+	h := New()
+	h.Write(block)
+	if got := hex.EncodeToString(h.Sum(nil)); got != want {
+		t.Errorf("sum(oneBlock) = %s, want %s", got, want)
+	}
+}
+
+func TestDropboxTwoBlocksAndAPartial(t *testing.T) {
+	data := make([]byte, 2*BlockSize+100)
+	for i := range data {
+		data[i] = byte(i)
+	}
+
+	var digests []byte
+	for i := 0; i < 2; i++ {
+		d := sha256.Sum256(data[i*BlockSize : (i+1)*BlockSize])
+		digests = append(digests, d[:]...)
+	}
+	lastDigest := sha256.Sum256(data[2*BlockSize:])
+	digests = append(digests, lastDigest[:]...)
+	finalDigest := sha256.Sum256(digests)
+	want := hex.EncodeToString(finalDigest[:])
+
+	h := New()
+	h.Write(data)
+	if got := hex.EncodeToString(h.Sum(nil)); got != want {
+		t.Errorf("sum = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,131 @@
+// Package gcid implements the GCID algorithm used by 115 and other
+// CRC/SHA1-block based providers for rapid-upload checks.
+//
+// The file is split into equal-sized blocks; each block's raw SHA-1
+// digest is written into one running, outer SHA-1, and Sum is only
+// taken once at the end. The block size is derived from the total file
+// size, so callers that know the size up front should call SetSize
+// before writing; without it, New falls back to a fixed 2 MiB block
+// size.
+package gcid
+
+import (
+	"crypto/sha1"
+	"encoding"
+	"hash"
+
+	"github.com/alist-org/alist/v3/pkg/utils/hashes/internal/checkpoint"
+)
+
+const defaultBlockSize = 2 * 1024 * 1024
+
+type digest struct {
+	blockSize int64
+	buf       []byte
+	outer     hash.Hash // running SHA-1 fed with every block's digest
+}
+
+// New returns a new hash.Hash computing GCID with the default block size.
+// Use SetSize beforehand when the total input length is known, so the
+// block size matches what the provider expects.
+func New() hash.Hash {
+	d := &digest{}
+	d.Reset()
+	return d
+}
+
+// SetSize derives the block size from the total size of the data that
+// will be written, mirroring 115's block-size scaling. It must be
+// called before the first Write.
+func SetSize(h hash.Hash, size int64) {
+	d := h.(*digest)
+	d.blockSize = calcBlockSize(size)
+}
+
+func calcBlockSize(size int64) int64 {
+	blockSize := int64(0x40000) // 256 KiB
+	for size/blockSize > 0x200 && blockSize < 0x200000 {
+		blockSize <<= 1
+	}
+	return blockSize
+}
+
+func (d *digest) Reset() {
+	d.blockSize = defaultBlockSize
+	d.buf = d.buf[:0]
+	d.outer = sha1.New()
+}
+
+func (d *digest) Write(p []byte) (n int, err error) {
+	n = len(p)
+	d.buf = append(d.buf, p...)
+	for int64(len(d.buf)) >= d.blockSize {
+		block := sha1.Sum(d.buf[:d.blockSize])
+		d.outer.Write(block[:])
+		d.buf = d.buf[d.blockSize:]
+	}
+	return n, nil
+}
+
+// Sum does not mutate the running state, so hashing can continue
+// after an intermediate Sum call.
+func (d *digest) Sum(b []byte) []byte {
+	outer := cloneSHA1(d.outer)
+	if len(d.buf) > 0 {
+		block := sha1.Sum(d.buf)
+		outer.Write(block[:])
+	}
+	return outer.Sum(b)
+}
+
+func (d *digest) Size() int { return sha1.Size }
+
+func (d *digest) BlockSize() int { return sha1.BlockSize }
+
+// cloneSHA1 duplicates a sha1 hash.Hash via its BinaryMarshaler state,
+// leaving the original untouched.
+func cloneSHA1(h hash.Hash) hash.Hash {
+	state, err := h.(encoding.BinaryMarshaler).MarshalBinary()
+	if err != nil {
+		panic("gcid: sha1 state is always marshalable: " + err.Error())
+	}
+	c := sha1.New()
+	if err = c.(encoding.BinaryUnmarshaler).UnmarshalBinary(state); err != nil {
+		panic("gcid: sha1 state is always unmarshalable: " + err.Error())
+	}
+	return c
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler so a digest can be
+// checkpointed mid-upload and resumed later, e.g. by MultiHasher.
+func (d *digest) MarshalBinary() ([]byte, error) {
+	outerState, err := d.outer.(encoding.BinaryMarshaler).MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	var buf []byte
+	buf = checkpoint.AppendUvarint(buf, uint64(d.blockSize))
+	buf = checkpoint.AppendLenPrefixed(buf, d.buf)
+	buf = checkpoint.AppendLenPrefixed(buf, outerState)
+	return buf, nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (d *digest) UnmarshalBinary(data []byte) error {
+	blockSize, rest, err := checkpoint.ReadUvarint(data)
+	if err != nil {
+		return err
+	}
+	buf, rest, err := checkpoint.ReadLenPrefixed(rest)
+	if err != nil {
+		return err
+	}
+	outerState, _, err := checkpoint.ReadLenPrefixed(rest)
+	if err != nil {
+		return err
+	}
+	d.blockSize = int64(blockSize)
+	d.buf = append([]byte(nil), buf...)
+	d.outer = sha1.New()
+	return d.outer.(encoding.BinaryUnmarshaler).UnmarshalBinary(outerState)
+}
@@ -0,0 +1,79 @@
+package gcid
+
+import (
+	"crypto/sha1"
+	"encoding"
+	"encoding/hex"
+	"testing"
+)
+
+func sum(data []byte) string {
+	h := New()
+	h.Write(data)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func TestGCIDEmpty(t *testing.T) {
+	// With no blocks written, GCID must fall back to a plain SHA-1 of
+	// nothing, not 20 zero bytes.
+	want := hex.EncodeToString(sha1.New().Sum(nil))
+	if got := sum(nil); got != want {
+		t.Errorf("sum(nil) = %s, want %s", got, want)
+	}
+}
+
+func TestGCIDSingleFullBlock(t *testing.T) {
+	block := make([]byte, defaultBlockSize)
+	blockDigest := sha1.Sum(block)
+	outer := sha1.New()
+	outer.Write(blockDigest[:])
+	want := hex.EncodeToString(outer.Sum(nil))
+	if got := sum(block); got != want {
+		t.Errorf("sum(oneBlock) = %s, want %s", got, want)
+	}
+}
+
+func TestGCIDMultipleBlocks(t *testing.T) {
+	const n = 3
+	data := make([]byte, n*defaultBlockSize)
+	for i := range data {
+		data[i] = byte(i)
+	}
+
+	var digests []byte
+	for i := 0; i < n; i++ {
+		d := sha1.Sum(data[i*defaultBlockSize : (i+1)*defaultBlockSize])
+		digests = append(digests, d[:]...)
+	}
+	finalDigest := sha1.Sum(digests)
+	want := hex.EncodeToString(finalDigest[:])
+
+	if got := sum(data); got != want {
+		t.Errorf("sum(%d blocks) = %s, want %s", n, got, want)
+	}
+}
+
+func TestGCIDMarshalUnmarshalRoundTrip(t *testing.T) {
+	data := make([]byte, defaultBlockSize+100)
+	for i := range data {
+		data[i] = byte(i)
+	}
+
+	h1 := New()
+	h1.Write(data[:defaultBlockSize])
+	state, err := h1.(encoding.BinaryMarshaler).MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary: %v", err)
+	}
+
+	h2 := New()
+	if err := h2.(encoding.BinaryUnmarshaler).UnmarshalBinary(state); err != nil {
+		t.Fatalf("UnmarshalBinary: %v", err)
+	}
+	h2.Write(data[defaultBlockSize:])
+
+	h1.Write(data[defaultBlockSize:])
+	if got, want := hex.EncodeToString(h2.Sum(nil)), hex.EncodeToString(h1.Sum(nil)); got != want {
+		t.Errorf("resumed sum = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,54 @@
+// Package checkpoint provides the varint-based encoding shared by the
+// MarshalBinary/UnmarshalBinary implementations of the hash.Hash types
+// under pkg/utils/hashes, so each one can be checkpointed mid-upload and
+// resumed later, e.g. by utils.MultiHasher.
+package checkpoint
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// AppendUvarint appends v to b as a varint.
+func AppendUvarint(b []byte, v uint64) []byte {
+	var tmp [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(tmp[:], v)
+	return append(b, tmp[:n]...)
+}
+
+// AppendLenPrefixed appends v to b preceded by its length as a varint.
+func AppendLenPrefixed(b, v []byte) []byte {
+	b = AppendUvarint(b, uint64(len(v)))
+	return append(b, v...)
+}
+
+// AppendBool appends b as a single 0/1 byte to buf.
+func AppendBool(buf []byte, b bool) []byte {
+	if b {
+		return append(buf, 1)
+	}
+	return append(buf, 0)
+}
+
+// ReadUvarint reads a varint off the front of b, returning its value and
+// the remaining bytes.
+func ReadUvarint(b []byte) (uint64, []byte, error) {
+	v, n := binary.Uvarint(b)
+	if n <= 0 {
+		return 0, nil, fmt.Errorf("checkpoint: invalid checkpoint")
+	}
+	return v, b[n:], nil
+}
+
+// ReadLenPrefixed reads a varint length followed by that many bytes off
+// the front of b, returning the slice and the remaining bytes.
+func ReadLenPrefixed(b []byte) ([]byte, []byte, error) {
+	length, rest, err := ReadUvarint(b)
+	if err != nil {
+		return nil, nil, err
+	}
+	if uint64(len(rest)) < length {
+		return nil, nil, fmt.Errorf("checkpoint: truncated checkpoint")
+	}
+	return rest[:length], rest[length:], nil
+}
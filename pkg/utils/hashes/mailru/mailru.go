@@ -0,0 +1,111 @@
+// Package mailru implements Mail.ru Cloud's content hash.
+//
+// The input is split into non-overlapping 1 MiB blocks. The first block's
+// SHA-1 is the running digest; every following block is combined by
+// hashing the concatenation of the running digest and the new block's
+// SHA-1, i.e. digest = SHA1(digest || SHA1(block)).
+package mailru
+
+import (
+	"crypto/sha1"
+	"fmt"
+	"hash"
+
+	"github.com/alist-org/alist/v3/pkg/utils/hashes/internal/checkpoint"
+)
+
+const BlockSize = 1024 * 1024
+
+type digest struct {
+	buf      []byte
+	combined [sha1.Size]byte
+	started  bool
+}
+
+// New returns a new hash.Hash computing the Mail.ru content hash.
+func New() hash.Hash {
+	d := &digest{}
+	d.Reset()
+	return d
+}
+
+func (d *digest) Reset() {
+	d.buf = d.buf[:0]
+	d.combined = [sha1.Size]byte{}
+	d.started = false
+}
+
+func (d *digest) Write(p []byte) (n int, err error) {
+	n = len(p)
+	d.buf = append(d.buf, p...)
+	for len(d.buf) >= BlockSize {
+		d.combine(sha1.Sum(d.buf[:BlockSize]))
+		d.buf = d.buf[BlockSize:]
+	}
+	return n, nil
+}
+
+func (d *digest) combine(block [sha1.Size]byte) {
+	if !d.started {
+		d.combined = block
+		d.started = true
+		return
+	}
+	h := sha1.New()
+	h.Write(d.combined[:])
+	h.Write(block[:])
+	copy(d.combined[:], h.Sum(nil))
+}
+
+// Sum does not mutate the running state, so hashing can continue
+// after an intermediate Sum call.
+func (d *digest) Sum(b []byte) []byte {
+	combined := d.combined
+	started := d.started
+	if len(d.buf) > 0 {
+		block := sha1.Sum(d.buf)
+		if !started {
+			combined = block
+			started = true
+		} else {
+			h := sha1.New()
+			h.Write(combined[:])
+			h.Write(block[:])
+			copy(combined[:], h.Sum(nil))
+		}
+	}
+	if !started {
+		// Nothing was ever written: fall back to a plain SHA-1 of
+		// nothing instead of 20 zero bytes.
+		return sha1.New().Sum(b)
+	}
+	return append(b, combined[:]...)
+}
+
+func (d *digest) Size() int { return sha1.Size }
+
+func (d *digest) BlockSize() int { return sha1.BlockSize }
+
+// MarshalBinary implements encoding.BinaryMarshaler so a digest can be
+// checkpointed mid-upload and resumed later, e.g. by MultiHasher.
+func (d *digest) MarshalBinary() ([]byte, error) {
+	buf := checkpoint.AppendLenPrefixed(nil, d.buf)
+	buf = append(buf, d.combined[:]...)
+	buf = checkpoint.AppendBool(buf, d.started)
+	return buf, nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (d *digest) UnmarshalBinary(data []byte) error {
+	buf, rest, err := checkpoint.ReadLenPrefixed(data)
+	if err != nil {
+		return err
+	}
+	if len(rest) < sha1.Size+1 {
+		return fmt.Errorf("mailru: truncated checkpoint")
+	}
+	d.buf = append([]byte(nil), buf...)
+	copy(d.combined[:], rest[:sha1.Size])
+	d.started = rest[sha1.Size] != 0
+	return nil
+}
@@ -0,0 +1,36 @@
+package mailru
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"testing"
+)
+
+func TestMailruEmpty(t *testing.T) {
+	emptyDigest := sha1.Sum(nil)
+	want := hex.EncodeToString(emptyDigest[:])
+	h := New()
+	if got := hex.EncodeToString(h.Sum(nil)); got != want {
+		t.Errorf("sum(nil) = %s, want %s", got, want)
+	}
+}
+
+func TestMailruTwoBlocks(t *testing.T) {
+	data := make([]byte, 2*BlockSize)
+	for i := range data {
+		data[i] = byte(i)
+	}
+	d1 := sha1.Sum(data[:BlockSize])
+	d2 := sha1.Sum(data[BlockSize:])
+
+	combine := sha1.New()
+	combine.Write(d1[:])
+	combine.Write(d2[:])
+	want := hex.EncodeToString(combine.Sum(nil))
+
+	h := New()
+	h.Write(data)
+	if got := hex.EncodeToString(h.Sum(nil)); got != want {
+		t.Errorf("sum = %s, want %s", got, want)
+	}
+}
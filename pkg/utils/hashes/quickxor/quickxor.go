@@ -0,0 +1,99 @@
+// Package quickxor implements Microsoft's QuickXorHash, used by OneDrive
+// to checksum uploaded files.
+//
+// The algorithm keeps a 160-bit shift register. Every input byte is XORed
+// into the register at a position that rotates by 11 bits per byte
+// (wrapping at 160 bits); the final sum XORs in the total input length as
+// a little-endian 64-bit value.
+package quickxor
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash"
+
+	"github.com/alist-org/alist/v3/pkg/utils/hashes/internal/checkpoint"
+)
+
+const (
+	widthInBits  = 160
+	widthInBytes = widthInBits / 8
+	shift        = 11
+)
+
+type digest struct {
+	data   [widthInBytes]byte
+	pos    int
+	length uint64
+}
+
+// New returns a new hash.Hash computing QuickXorHash.
+func New() hash.Hash {
+	d := &digest{}
+	d.Reset()
+	return d
+}
+
+func (d *digest) Reset() {
+	d.data = [widthInBytes]byte{}
+	d.pos = 0
+	d.length = 0
+}
+
+func (d *digest) Write(p []byte) (n int, err error) {
+	for _, b := range p {
+		bytePos := d.pos / 8
+		bitPos := uint(d.pos % 8)
+		if bitPos == 0 {
+			d.data[bytePos] ^= b
+		} else {
+			d.data[bytePos] ^= b << bitPos
+			d.data[(bytePos+1)%widthInBytes] ^= b >> (8 - bitPos)
+		}
+		d.pos = (d.pos + shift) % widthInBits
+	}
+	d.length += uint64(len(p))
+	return len(p), nil
+}
+
+func (d *digest) Sum(b []byte) []byte {
+	out := d.data
+	var lenBytes [8]byte
+	binary.LittleEndian.PutUint64(lenBytes[:], d.length)
+	for i, lb := range lenBytes {
+		out[widthInBytes-8+i] ^= lb
+	}
+	return append(b, out[:]...)
+}
+
+func (d *digest) Size() int { return widthInBytes }
+
+func (d *digest) BlockSize() int { return 64 }
+
+// MarshalBinary implements encoding.BinaryMarshaler so a digest can be
+// checkpointed mid-upload and resumed later, e.g. by MultiHasher.
+func (d *digest) MarshalBinary() ([]byte, error) {
+	buf := append([]byte(nil), d.data[:]...)
+	buf = checkpoint.AppendUvarint(buf, uint64(d.pos))
+	buf = checkpoint.AppendUvarint(buf, d.length)
+	return buf, nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (d *digest) UnmarshalBinary(data []byte) error {
+	if len(data) < widthInBytes {
+		return fmt.Errorf("quickxor: truncated checkpoint")
+	}
+	copy(d.data[:], data[:widthInBytes])
+	pos, rest, err := checkpoint.ReadUvarint(data[widthInBytes:])
+	if err != nil {
+		return err
+	}
+	length, _, err := checkpoint.ReadUvarint(rest)
+	if err != nil {
+		return err
+	}
+	d.pos = int(pos)
+	d.length = length
+	return nil
+}
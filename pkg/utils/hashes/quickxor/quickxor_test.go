@@ -0,0 +1,39 @@
+package quickxor
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+func TestQuickXorEmpty(t *testing.T) {
+	h := New()
+	got := hex.EncodeToString(h.Sum(nil))
+	want := hex.EncodeToString(make([]byte, widthInBytes))
+	if got != want {
+		t.Errorf("sum(nil) = %s, want %s", got, want)
+	}
+}
+
+func TestQuickXorWriteOrderIndependentOfChunking(t *testing.T) {
+	data := make([]byte, 1000)
+	for i := range data {
+		data[i] = byte(i)
+	}
+
+	whole := New()
+	whole.Write(data)
+
+	chunked := New()
+	for i := 0; i < len(data); i += 7 {
+		end := i + 7
+		if end > len(data) {
+			end = len(data)
+		}
+		chunked.Write(data[i:end])
+	}
+
+	got, want := hex.EncodeToString(chunked.Sum(nil)), hex.EncodeToString(whole.Sum(nil))
+	if got != want {
+		t.Errorf("chunked sum = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,114 @@
+package utils
+
+import (
+	"encoding/base32"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+)
+
+// multihash function codes, see https://github.com/multiformats/multicodec
+// Codes in the 0x300000-0x3fffff range are reserved for private use, which
+// is where alist's proprietary hash types live since they have no official
+// multicodec assignment.
+var hashToMultihashCode = map[*HashType]uint64{
+	MD5:          0xd5,
+	SHA1:         0x11,
+	SHA256:       0x12,
+	SHA512:       0x13,
+	DropboxHash:  0x300001,
+	QuickXorHash: 0x300002,
+	MailruHash:   0x300003,
+	GCID:         0x300004,
+	CRC32:        0x300005,
+	CRC32C:       0x300006,
+	CRC64:        0x300007,
+	CRC64ECMA:    0x300008,
+	BLAKE3:       0x1e,
+}
+
+var multihashCodeToHash = map[uint64]*HashType{}
+
+func init() {
+	for ht, code := range hashToMultihashCode {
+		multihashCodeToHash[code] = ht
+	}
+}
+
+// MarshalMultihash encodes the digest stored for ht as a multihash:
+// a varint function code, a varint digest length, then the raw digest.
+func (hi HashInfo) MarshalMultihash(ht *HashType) ([]byte, error) {
+	str := hi.GetHash(ht)
+	if len(str) == 0 {
+		return nil, ErrUnsupported
+	}
+	digest, err := hex.DecodeString(str)
+	if err != nil {
+		return nil, err
+	}
+	code, ok := hashToMultihashCode[ht]
+	if !ok {
+		return nil, fmt.Errorf("utils: no multihash code registered for hash type %q", ht.Name)
+	}
+	buf := make([]byte, 2*binary.MaxVarintLen64+len(digest))
+	n := binary.PutUvarint(buf, code)
+	n += binary.PutUvarint(buf[n:], uint64(len(digest)))
+	n += copy(buf[n:], digest)
+	return buf[:n], nil
+}
+
+// ParseMultihash decodes a multihash produced by MarshalMultihash back
+// into a HashInfo, looking up the HashType from its function code.
+func ParseMultihash(b []byte) (HashInfo, error) {
+	code, n := binary.Uvarint(b)
+	if n <= 0 {
+		return HashInfo{}, fmt.Errorf("utils: invalid multihash function code")
+	}
+	b = b[n:]
+	length, n := binary.Uvarint(b)
+	if n <= 0 {
+		return HashInfo{}, fmt.Errorf("utils: invalid multihash digest length")
+	}
+	b = b[n:]
+	if uint64(len(b)) < length {
+		return HashInfo{}, fmt.Errorf("utils: truncated multihash digest")
+	}
+	ht, ok := multihashCodeToHash[code]
+	if !ok {
+		return HashInfo{}, fmt.Errorf("utils: unknown multihash function code %#x", code)
+	}
+	return NewHashInfo(ht, hex.EncodeToString(b[:length])), nil
+}
+
+const cidv1RawCodec = 0x55
+
+// base32Lower is RFC4648's lowercase alphabet, unpadded, matching what
+// the multibase "b" prefix below promises decoders.
+var base32Lower = base32.NewEncoding("abcdefghijklmnopqrstuvwxyz234567").WithPadding(base32.NoPadding)
+
+// ToCID encodes the strongest hash carried by hi as a base32, CIDv1,
+// raw-leaf content identifier (https://github.com/multiformats/cid),
+// so it can be shared with IPFS-compatible tooling without exposing
+// alist's internal *HashType pointer identity. "Strongest" uses the same
+// HashType.strength ordering as HashSet.Overlap, via strongestFirst.
+func (hi HashInfo) ToCID() (string, error) {
+	for _, ht := range strongestFirst() {
+		mh, err := hi.MarshalMultihash(ht)
+		if err != nil {
+			continue
+		}
+		buf := make([]byte, 0, 2+len(mh))
+		buf = appendUvarint(buf, 1) // CID version
+		buf = appendUvarint(buf, cidv1RawCodec)
+		buf = append(buf, mh...)
+		// "b" is the multibase prefix for lower-case, unpadded base32
+		return "b" + base32Lower.EncodeToString(buf), nil
+	}
+	return "", ErrUnsupported
+}
+
+func appendUvarint(b []byte, v uint64) []byte {
+	var tmp [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(tmp[:], v)
+	return append(b, tmp[:n]...)
+}
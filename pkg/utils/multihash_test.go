@@ -0,0 +1,57 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMarshalParseMultihashRoundTrip(t *testing.T) {
+	hi := NewHashInfo(SHA256, HashData(SHA256, []byte("hello world")))
+
+	mh, err := hi.MarshalMultihash(SHA256)
+	if err != nil {
+		t.Fatalf("MarshalMultihash: %v", err)
+	}
+
+	parsed, err := ParseMultihash(mh)
+	if err != nil {
+		t.Fatalf("ParseMultihash: %v", err)
+	}
+	if got, want := parsed.GetHash(SHA256), hi.GetHash(SHA256); got != want {
+		t.Errorf("round-tripped digest = %s, want %s", got, want)
+	}
+}
+
+func TestToCIDIsLowercaseMultibase(t *testing.T) {
+	hi := NewHashInfo(MD5, HashData(MD5, []byte("hello world")))
+	hi.h[SHA256] = HashData(SHA256, []byte("hello world"))
+	hi.h[BLAKE3] = HashData(BLAKE3, []byte("hello world"))
+
+	cid, err := hi.ToCID()
+	if err != nil {
+		t.Fatalf("ToCID: %v", err)
+	}
+	if !strings.HasPrefix(cid, "b") {
+		t.Fatalf("CID %q does not start with the base32 multibase prefix %q", cid, "b")
+	}
+	// ToCID must pick the strongest hash (BLAKE3, registered last), not MD5.
+	wantMH, err := hi.MarshalMultihash(BLAKE3)
+	if err != nil {
+		t.Fatalf("MarshalMultihash(BLAKE3): %v", err)
+	}
+	wantBuf := appendUvarint(appendUvarint(nil, 1), cidv1RawCodec)
+	wantBuf = append(wantBuf, wantMH...)
+	want := "b" + base32Lower.EncodeToString(wantBuf)
+	if cid != want {
+		t.Errorf("ToCID = %q, want %q (strongest hash, lowercase base32)", cid, want)
+	}
+
+	// "b" multibase specifically promises lowercase, unpadded RFC4648;
+	// decoding with that exact alphabet must succeed.
+	if _, err := base32Lower.DecodeString(cid[1:]); err != nil {
+		t.Errorf("lowercase base32 decode of %q failed: %v", cid, err)
+	}
+	if strings.ToLower(cid) != cid {
+		t.Errorf("CID %q contains uppercase characters, multibase \"b\" requires lowercase", cid)
+	}
+}